@@ -0,0 +1,41 @@
+package httputil
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Encoder writes successive JSON values to an underlying stream, as
+// returned by Codec.NewEncoder.
+type Encoder interface {
+	Encode(v interface{}) error
+}
+
+// Codec abstracts the JSON implementation used to marshal response bodies,
+// so it can be swapped for a faster drop-in (json-iterator, go-json) without
+// touching call sites. The default is the standard library's encoding/json.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	NewEncoder(w io.Writer) Encoder
+}
+
+// defaultCodec is used by RespondJSON and RespondJSONStream. Build-tagged
+// files (codec_jsoniter.go, codec_gojson.go) may override it from init.
+var defaultCodec Codec = stdCodec{}
+
+// SetCodec overrides the package-wide JSON codec. It is not safe to call
+// concurrently with requests being served; call it during startup.
+func SetCodec(c Codec) {
+	defaultCodec = c
+}
+
+// stdCodec is the default Codec, backed by encoding/json.
+type stdCodec struct{}
+
+func (stdCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdCodec) NewEncoder(w io.Writer) Encoder {
+	return json.NewEncoder(w)
+}
@@ -0,0 +1,25 @@
+//go:build gojson
+
+package httputil
+
+import (
+	"io"
+
+	gojson "github.com/goccy/go-json"
+)
+
+func init() {
+	defaultCodec = gojsonCodec{}
+}
+
+// gojsonCodec adapts github.com/goccy/go-json to the Codec interface. Built
+// only with -tags gojson.
+type gojsonCodec struct{}
+
+func (gojsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return gojson.Marshal(v)
+}
+
+func (gojsonCodec) NewEncoder(w io.Writer) Encoder {
+	return gojson.NewEncoder(w)
+}
@@ -0,0 +1,27 @@
+//go:build jsoniter
+
+package httputil
+
+import (
+	"io"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+func init() {
+	defaultCodec = jsoniterCodec{api: jsoniter.ConfigCompatibleWithStandardLibrary}
+}
+
+// jsoniterCodec adapts github.com/json-iterator/go to the Codec interface.
+// Built only with -tags jsoniter.
+type jsoniterCodec struct {
+	api jsoniter.API
+}
+
+func (c jsoniterCodec) Marshal(v interface{}) ([]byte, error) {
+	return c.api.Marshal(v)
+}
+
+func (c jsoniterCodec) NewEncoder(w io.Writer) Encoder {
+	return c.api.NewEncoder(w)
+}
@@ -0,0 +1,87 @@
+package httputil
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStdCodec_MarshalAndNewEncoder(t *testing.T) {
+	c := stdCodec{}
+
+	body, err := c.Marshal(map[string]int{"a": 1})
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	if string(body) != `{"a":1}` {
+		t.Errorf("Marshal = %s, want %s", body, `{"a":1}`)
+	}
+
+	var buf bytes.Buffer
+	if err := c.NewEncoder(&buf).Encode(map[string]int{"b": 2}); err != nil {
+		t.Fatalf("Encode returned error: %v", err)
+	}
+	if buf.String() != "{\"b\":2}\n" {
+		t.Errorf("Encode wrote %q, want %q", buf.String(), "{\"b\":2}\n")
+	}
+}
+
+func TestSetCodec_OverridesDefault(t *testing.T) {
+	t.Cleanup(func() { defaultCodec = stdCodec{} })
+
+	// A distinct type, so the check below fails if SetCodec leaves the
+	// stdCodec{} default in place.
+	custom := struct{ stdCodec }{}
+	SetCodec(custom)
+
+	if defaultCodec != Codec(custom) {
+		t.Error("expected SetCodec to replace the package-wide defaultCodec")
+	}
+}
+
+func TestRespondJSON_WritesBodyAndContentType(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	RespondJSON(rec, http.StatusCreated, map[string]string{"id": "42"})
+
+	if rec.Code != http.StatusCreated {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response body: %v", err)
+	}
+	if body["id"] != "42" {
+		t.Errorf("id = %q, want %q", body["id"], "42")
+	}
+}
+
+func TestRespondJSON_EncodeErrorProducesProblemResponse(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	// +Inf cannot be represented in JSON, so encoding/json fails on it -
+	// this exercises the "buffer before writing status" guarantee.
+	RespondJSON(rec, http.StatusOK, math.Inf(1))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d (encode error must not leak the original 200)", rec.Code, http.StatusInternalServerError)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Content-Type = %q, want application/problem+json", ct)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode problem body: %v", err)
+	}
+	if body["status"] != float64(http.StatusInternalServerError) {
+		t.Errorf("status in body = %v, want %v", body["status"], http.StatusInternalServerError)
+	}
+}
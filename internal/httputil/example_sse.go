@@ -0,0 +1,39 @@
+package httputil
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// ScenarioProgress is one intermediate result emitted while a scenario is
+// being recomputed.
+type ScenarioProgress struct {
+	Step    int         `json:"step"`
+	Total   int         `json:"total"`
+	Message string      `json:"message"`
+	Result  interface{} `json:"result,omitempty"`
+}
+
+// ExampleScenarioRecomputeHandler shows how to wire a long-running
+// scenario recompute to RespondSSE: recompute is handed the request
+// context (so it can stop early on client disconnect) and the resuming
+// Last-Event-ID, and returns a channel of progress updates to stream.
+func ExampleScenarioRecomputeHandler(recompute func(r *http.Request, lastEventID string) <-chan ScenarioProgress) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		progress := recompute(r, r.Header.Get("Last-Event-ID"))
+
+		events := make(chan Event)
+		go func() {
+			defer close(events)
+			for i := range progress {
+				select {
+				case events <- Event{ID: strconv.Itoa(i.Step), Type: "progress", Data: i}:
+				case <-r.Context().Done():
+					return
+				}
+			}
+		}()
+
+		RespondSSE(w, r, events)
+	}
+}
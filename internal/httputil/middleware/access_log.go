@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// AccessLog emits one structured log line per request: method, path,
+// status, bytes written and duration.
+func AccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := newStatusRecorder(w)
+
+		next.ServeHTTP(rec, r)
+
+		log.Printf("method=%s path=%s status=%d bytes=%d duration=%s",
+			r.Method, r.URL.Path, rec.status, rec.bytes, time.Since(start))
+	})
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// MetricsSink receives one observation per request from Metrics.
+// Implementations adapt this to whatever metrics backend the app wires up
+// (Prometheus, StatsD, ...); the middleware itself stays backend-agnostic.
+type MetricsSink interface {
+	ObserveRequest(route, method string, status int, duration time.Duration)
+}
+
+// Metrics records one observation per request against sink, keyed by the
+// matched gorilla/mux route template (falling back to the raw URL path for
+// requests that didn't match a registered route, e.g. 404s).
+func Metrics(sink MetricsSink) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := newStatusRecorder(w)
+
+			next.ServeHTTP(rec, r)
+
+			route := r.URL.Path
+			if matched := mux.CurrentRoute(r); matched != nil {
+				if tmpl, err := matched.GetPathTemplate(); err == nil {
+					route = tmpl
+				}
+			}
+			sink.ObserveRequest(route, r.Method, rec.status, time.Since(start))
+		})
+	}
+}
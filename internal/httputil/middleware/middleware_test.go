@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kartoza/decision-theatre/internal/httputil"
+)
+
+func TestRequestID_GeneratesAndEchoes(t *testing.T) {
+	var gotFromContext string
+	router := mux.NewRouter()
+	router.Use(RequestID)
+	router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		gotFromContext = httputil.RequestIDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	header := rec.Header().Get(RequestIDHeader)
+	if header == "" {
+		t.Fatal("expected X-Request-ID response header to be set")
+	}
+	if gotFromContext != header {
+		t.Fatalf("context request id %q does not match response header %q", gotFromContext, header)
+	}
+}
+
+func TestRequestID_ReusesInboundHeader(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(RequestID)
+	router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "client-supplied-id")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(RequestIDHeader); got != "client-supplied-id" {
+		t.Fatalf("expected inbound request id to be echoed, got %q", got)
+	}
+}
+
+func TestRecoverer_ConvertsPanicToProblemJSON(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(RequestID)
+	router.Use(Recoverer)
+	router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "panic-test-id")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Fatalf("expected application/problem+json content type, got %q", ct)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode problem body: %v", err)
+	}
+	if body["request_id"] != "panic-test-id" {
+		t.Fatalf("expected request_id %q to propagate into problem body, got %v", "panic-test-id", body["request_id"])
+	}
+	if body["status"] != float64(http.StatusInternalServerError) {
+		t.Fatalf("expected status 500 in problem body, got %v", body["status"])
+	}
+}
+
+func TestRecoverer_RepanicsOnErrAbortHandler(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(Recoverer)
+	router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+
+	defer func() {
+		rec := recover()
+		if rec != http.ErrAbortHandler {
+			t.Fatalf("expected http.ErrAbortHandler to propagate past Recoverer, got %v", rec)
+		}
+	}()
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	t.Fatal("expected ServeHTTP to panic with http.ErrAbortHandler")
+}
+
+type recordingSink struct {
+	route    string
+	method   string
+	status   int
+	duration time.Duration
+	calls    int
+}
+
+func (s *recordingSink) ObserveRequest(route, method string, status int, duration time.Duration) {
+	s.route = route
+	s.method = method
+	s.status = status
+	s.duration = duration
+	s.calls++
+}
+
+func TestStatusRecorder_ForwardsFlush(t *testing.T) {
+	underlying := httptest.NewRecorder()
+	rec := newStatusRecorder(underlying)
+
+	flusher, ok := http.ResponseWriter(rec).(http.Flusher)
+	if !ok {
+		t.Fatal("expected *statusRecorder to implement http.Flusher")
+	}
+	flusher.Flush()
+
+	if !underlying.Flushed {
+		t.Fatal("expected Flush to be forwarded to the underlying ResponseWriter")
+	}
+}
+
+func TestStatusRecorder_HijackFailsCleanlyWhenUnsupported(t *testing.T) {
+	// httptest.ResponseRecorder does not implement http.Hijacker.
+	rec := newStatusRecorder(httptest.NewRecorder())
+
+	hijacker, ok := http.ResponseWriter(rec).(http.Hijacker)
+	if !ok {
+		t.Fatal("expected *statusRecorder to implement http.Hijacker")
+	}
+	if _, _, err := hijacker.Hijack(); err == nil {
+		t.Fatal("expected Hijack to return an error when the underlying writer does not support it")
+	}
+}
+
+func TestMetrics_RecordsMatchedRouteTemplate(t *testing.T) {
+	sink := &recordingSink{}
+	router := mux.NewRouter()
+	router.Use(Metrics(sink))
+	router.HandleFunc("/scenarios/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/scenarios/42", nil))
+
+	if sink.calls != 1 {
+		t.Fatalf("expected exactly one observation, got %d", sink.calls)
+	}
+	if sink.route != "/scenarios/{id}" {
+		t.Fatalf("expected route template, got %q", sink.route)
+	}
+	if sink.status != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", sink.status)
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// statusRecorder wraps an http.ResponseWriter to capture the status code
+// and byte count written, for AccessLog and Metrics. It forwards Flush and
+// Hijack to the underlying writer when available, so routes using
+// httputil.RespondSSE or httputil.Upgrade keep working when composed with
+// AccessLog/Metrics via router.Use.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func newStatusRecorder(w http.ResponseWriter) *statusRecorder {
+	return &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Flush forwards to the underlying ResponseWriter's Flush when it supports
+// streaming, and is a no-op otherwise (matching the zero-value behavior
+// http.Flusher implementations already have for unbuffered writes).
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack forwards to the underlying ResponseWriter's Hijack, as used by
+// httputil.Upgrade to take over the connection for WebSocket framing.
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("httputil/middleware: underlying ResponseWriter does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
+// Unwrap exposes the wrapped ResponseWriter to http.ResponseController, so
+// callers using it can reach the underlying writer through this recorder.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
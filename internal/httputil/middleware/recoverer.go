@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/kartoza/decision-theatre/internal/httputil"
+)
+
+// Recoverer catches panics in next and converts them into an RFC 7807
+// Problem+JSON 500 response carrying the request's correlation id, instead
+// of the bare connection reset net/http leaves behind by default.
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				if rec == http.ErrAbortHandler {
+					// The handler deliberately aborted the response;
+					// re-panic so net/http closes the connection without
+					// logging or writing a body over whatever bytes already
+					// went out.
+					panic(rec)
+				}
+				log.Printf("httputil/middleware: panic recovered: %v\n%s", rec, debug.Stack())
+				httputil.RespondProblem(w, r, httputil.ProblemInternal, "internal server error")
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,32 @@
+// Package middleware provides gorilla/mux-compatible middleware for
+// decision-theatre's HTTP servers: request correlation ids, panic recovery,
+// access logging and metrics.
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/kartoza/decision-theatre/internal/httputil"
+)
+
+// RequestIDHeader is the header RequestID reads an inbound id from, and
+// echoes the resolved id back on.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID stamps every request with a correlation id: it reuses
+// X-Request-ID from the incoming request when present, otherwise generates
+// one, stores it on the request context via httputil.WithRequestID (so
+// RespondProblem can attach it to error bodies), and echoes it back on the
+// response. RespondError takes no request and does not attach the id.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" {
+			id = uuid.NewString()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(httputil.WithRequestID(r.Context(), id)))
+	})
+}
@@ -0,0 +1,139 @@
+package httputil
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// MIME types understood by Respond out of the box.
+const (
+	MIMEJSON        = "application/json"
+	MIMEProblemJSON = "application/problem+json"
+	MIMEProtobuf    = "application/x-protobuf"
+	MIMEJSONProto   = "application/json; proto=1"
+)
+
+// ResponseEncoder writes data as a complete HTTP response body - headers,
+// status line and all - for one negotiated content type.
+type ResponseEncoder interface {
+	Encode(w http.ResponseWriter, status int, data interface{}) error
+}
+
+// encoders maps a negotiated MIME type to the ResponseEncoder that handles
+// it. Populated by init and extendable via RegisterEncoder.
+var encoders = map[string]ResponseEncoder{
+	MIMEJSON:        jsonEncoder{},
+	MIMEProblemJSON: problemEncoder{},
+	MIMEProtobuf:    protobufEncoder{},
+	MIMEJSONProto:   jsonpbEncoder{},
+}
+
+// RegisterEncoder adds or replaces the ResponseEncoder used for mime, so
+// handlers can serve additional representations (e.g. a scenario-specific
+// protobuf variant) through Respond without duplicating negotiation logic.
+func RegisterEncoder(mime string, enc ResponseEncoder) {
+	encoders[mime] = enc
+}
+
+// Respond negotiates a response representation from the request's Accept
+// header and writes data using the matching encoder, falling back to JSON
+// when Accept is absent, "*/*", or names a type with no registered encoder.
+func Respond(w http.ResponseWriter, r *http.Request, status int, data interface{}) {
+	enc := negotiate(r.Header.Get("Accept"))
+	if err := enc.Encode(w, status, data); err != nil {
+		log.Printf("httputil: error encoding negotiated response: %v", err)
+	}
+}
+
+// negotiate picks a registered encoder for the Accept header, taking the
+// first acceptable entry (in header order) that has one, and falling back
+// to JSON. It does not attempt full RFC 7231 q-value ranking. Entries are
+// matched both verbatim (so "application/json; proto=1" can be registered
+// as its own type) and with parameters stripped (so a plain "application/json"
+// still matches after trimming "; proto=1").
+func negotiate(accept string) ResponseEncoder {
+	for _, part := range strings.Split(accept, ",") {
+		candidate := strings.TrimSpace(part)
+		if candidate == "" || candidate == "*/*" {
+			continue
+		}
+		if enc, ok := encoders[candidate]; ok {
+			return enc
+		}
+		bare := strings.TrimSpace(strings.SplitN(candidate, ";", 2)[0])
+		if enc, ok := encoders[bare]; ok {
+			return enc
+		}
+	}
+	return encoders[MIMEJSON]
+}
+
+// jsonEncoder writes data through the package's Codec, as RespondJSON does.
+type jsonEncoder struct{}
+
+func (jsonEncoder) Encode(w http.ResponseWriter, status int, data interface{}) error {
+	RespondJSON(w, status, data)
+	return nil
+}
+
+// problemEncoder writes an RFC 7807 Problem as application/problem+json,
+// using the Problem's own Status rather than the status Respond was called
+// with. Data that isn't a Problem falls back to plain JSON, same as
+// jsonpbEncoder/protobufEncoder do for data that doesn't match their type.
+type problemEncoder struct{}
+
+func (problemEncoder) Encode(w http.ResponseWriter, status int, data interface{}) error {
+	switch p := data.(type) {
+	case Problem:
+		writeProblem(w, p)
+	case *Problem:
+		writeProblem(w, *p)
+	default:
+		RespondJSON(w, status, data)
+	}
+	return nil
+}
+
+// jsonpbEncoder writes a proto.Message as JSON via protojson, with
+// unpopulated fields emitted so scenario/geometry payloads are stable
+// across optional fields for JSON-only consumers.
+type jsonpbEncoder struct{}
+
+func (jsonpbEncoder) Encode(w http.ResponseWriter, status int, data interface{}) error {
+	msg, ok := data.(proto.Message)
+	if !ok {
+		RespondJSON(w, status, data)
+		return nil
+	}
+	body, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", MIMEJSONProto)
+	w.WriteHeader(status)
+	_, err = w.Write(body)
+	return err
+}
+
+// protobufEncoder writes a proto.Message as binary protobuf.
+type protobufEncoder struct{}
+
+func (protobufEncoder) Encode(w http.ResponseWriter, status int, data interface{}) error {
+	msg, ok := data.(proto.Message)
+	if !ok {
+		RespondJSON(w, status, data)
+		return nil
+	}
+	body, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", MIMEProtobuf)
+	w.WriteHeader(status)
+	_, err = w.Write(body)
+	return err
+}
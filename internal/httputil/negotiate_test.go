@@ -0,0 +1,138 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestRespond_DefaultsToJSONWhenNoAcceptHeader(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	Respond(rec, req, http.StatusOK, map[string]string{"scenario": "flood-2030"})
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+}
+
+func TestRespond_NegotiatesProblemJSON(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", MIMEProblemJSON)
+
+	Respond(rec, req, http.StatusOK, Problem{
+		Type:   ProblemNotFound.Type,
+		Title:  ProblemNotFound.Title,
+		Status: http.StatusNotFound,
+		Detail: "scenario 42 not found",
+	})
+
+	if ct := rec.Header().Get("Content-Type"); ct != MIMEProblemJSON {
+		t.Errorf("Content-Type = %q, want %q", ct, MIMEProblemJSON)
+	}
+	// problemEncoder uses the Problem's own Status, not the status Respond
+	// was called with.
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestRespond_NegotiatesProblemJSON_FallsBackToJSONForNonProblemData(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", MIMEProblemJSON)
+
+	Respond(rec, req, http.StatusOK, map[string]string{"ok": "true"})
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json for non-Problem data", ct)
+	}
+}
+
+func TestRespond_NegotiatesProtobuf(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", MIMEProtobuf)
+
+	msg := wrapperspb.String("flood-2030")
+	Respond(rec, req, http.StatusOK, msg)
+
+	if ct := rec.Header().Get("Content-Type"); ct != MIMEProtobuf {
+		t.Errorf("Content-Type = %q, want %q", ct, MIMEProtobuf)
+	}
+
+	var got wrapperspb.StringValue
+	if err := proto.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal protobuf body: %v", err)
+	}
+	if got.Value != "flood-2030" {
+		t.Errorf("Value = %q, want %q", got.Value, "flood-2030")
+	}
+}
+
+func TestRespond_NegotiatesJSONPB(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", MIMEJSONProto)
+
+	Respond(rec, req, http.StatusOK, wrapperspb.String("flood-2030"))
+
+	if ct := rec.Header().Get("Content-Type"); ct != MIMEJSONProto {
+		t.Errorf("Content-Type = %q, want %q", ct, MIMEJSONProto)
+	}
+
+	var got wrapperspb.StringValue
+	if err := protojson.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode jsonpb body: %v", err)
+	}
+	if got.Value != "flood-2030" {
+		t.Errorf("Value = %q, want %q", got.Value, "flood-2030")
+	}
+}
+
+func TestRespond_NonProtoDataFallsBackToJSONForProtobufAccept(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", MIMEProtobuf)
+
+	Respond(rec, req, http.StatusOK, map[string]string{"not": "a proto message"})
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json for non-proto data", ct)
+	}
+}
+
+func TestRegisterEncoder_IsUsedByRespond(t *testing.T) {
+	const mime = "application/vnd.decision-theatre.test+json"
+	t.Cleanup(func() { delete(encoders, mime) })
+
+	// protobufEncoder, unlike the JSON fallback, writes MIMEProtobuf, so the
+	// Content-Type shows which encoder Respond picked.
+	RegisterEncoder(mime, protobufEncoder{})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", mime)
+
+	Respond(rec, req, http.StatusOK, wrapperspb.String("ok"))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != MIMEProtobuf {
+		t.Errorf("Content-Type = %q, want %q from the registered encoder", ct, MIMEProtobuf)
+	}
+}
+
+func TestNegotiate_IgnoresWildcardAndFallsBackToJSON(t *testing.T) {
+	enc := negotiate("*/*, text/html")
+	if _, ok := enc.(jsonEncoder); !ok {
+		t.Errorf("expected jsonEncoder fallback, got %T", enc)
+	}
+}
@@ -0,0 +1,121 @@
+package httputil
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// ProblemType is a catalog entry describing a class of error as defined by
+// RFC 7807 (application/problem+json).
+type ProblemType struct {
+	Type   string
+	Title  string
+	Status int
+}
+
+// Known problem types shared across the app's handlers. Type is a stable
+// URI identifying the error class; it does not need to resolve to anything.
+var (
+	ProblemValidation = ProblemType{
+		Type:   "https://kartoza.github.io/decision-theatre/problems/validation",
+		Title:  "Validation Failed",
+		Status: http.StatusBadRequest,
+	}
+	ProblemNotFound = ProblemType{
+		Type:   "https://kartoza.github.io/decision-theatre/problems/not-found",
+		Title:  "Not Found",
+		Status: http.StatusNotFound,
+	}
+	ProblemConflict = ProblemType{
+		Type:   "https://kartoza.github.io/decision-theatre/problems/conflict",
+		Title:  "Conflict",
+		Status: http.StatusConflict,
+	}
+	ProblemInternal = ProblemType{
+		Type:   "https://kartoza.github.io/decision-theatre/problems/internal",
+		Title:  "Internal Server Error",
+		Status: http.StatusInternalServerError,
+	}
+)
+
+// Problem is an RFC 7807 "problem detail" error body.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]interface{}
+}
+
+// MarshalJSON flattens Extensions alongside the standard RFC 7807 members,
+// per the spec's allowance for problem-type-specific extension members.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	out := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		out[k] = v
+	}
+	out["type"] = p.Type
+	out["title"] = p.Title
+	out["status"] = p.Status
+	if p.Detail != "" {
+		out["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		out["instance"] = p.Instance
+	}
+	return json.Marshal(out)
+}
+
+// problemTypeForStatus maps a bare HTTP status to a catalog entry, falling
+// back to the RFC 7807 "about:blank" type for statuses with no dedicated
+// entry.
+func problemTypeForStatus(status int) ProblemType {
+	switch status {
+	case http.StatusBadRequest:
+		return ProblemValidation
+	case http.StatusNotFound:
+		return ProblemNotFound
+	case http.StatusConflict:
+		return ProblemConflict
+	case http.StatusInternalServerError:
+		return ProblemInternal
+	default:
+		return ProblemType{Type: "about:blank", Title: http.StatusText(status), Status: status}
+	}
+}
+
+// RespondProblem writes an RFC 7807 application/problem+json body for pt,
+// filling in Detail and merging extensions (e.g. a "code" field) into the
+// top-level object. The request's correlation id, if any, is attached as a
+// "request_id" extension.
+func RespondProblem(w http.ResponseWriter, r *http.Request, pt ProblemType, detail string, extensions ...map[string]interface{}) {
+	p := Problem{
+		Type:   pt.Type,
+		Title:  pt.Title,
+		Status: pt.Status,
+		Detail: detail,
+	}
+	id := RequestIDFromContext(r.Context())
+	if len(extensions) > 0 || id != "" {
+		p.Extensions = make(map[string]interface{})
+		for _, ext := range extensions {
+			for k, v := range ext {
+				p.Extensions[k] = v
+			}
+		}
+	}
+	if id != "" {
+		p.Extensions["request_id"] = id
+	}
+	writeProblem(w, p)
+}
+
+func writeProblem(w http.ResponseWriter, p Problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		log.Printf("httputil: error encoding problem response: %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package httputil
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProblem_MarshalJSON_FlattensExtensions(t *testing.T) {
+	p := Problem{
+		Type:   ProblemValidation.Type,
+		Title:  ProblemValidation.Title,
+		Status: http.StatusBadRequest,
+		Detail: "missing field: name",
+		Extensions: map[string]interface{}{
+			"code": "missing_field",
+		},
+	}
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("MarshalJSON returned error: %v", err)
+	}
+
+	var out map[string]interface{}
+	if err := json.Unmarshal(body, &out); err != nil {
+		t.Fatalf("failed to decode marshaled problem: %v", err)
+	}
+
+	if out["type"] != ProblemValidation.Type {
+		t.Errorf("type = %v, want %v", out["type"], ProblemValidation.Type)
+	}
+	if out["title"] != ProblemValidation.Title {
+		t.Errorf("title = %v, want %v", out["title"], ProblemValidation.Title)
+	}
+	if out["status"] != float64(http.StatusBadRequest) {
+		t.Errorf("status = %v, want %v", out["status"], http.StatusBadRequest)
+	}
+	if out["detail"] != "missing field: name" {
+		t.Errorf("detail = %v, want %q", out["detail"], "missing field: name")
+	}
+	if out["code"] != "missing_field" {
+		t.Errorf("code extension = %v, want %q", out["code"], "missing_field")
+	}
+}
+
+func TestProblem_MarshalJSON_OmitsEmptyDetailAndInstance(t *testing.T) {
+	body, err := json.Marshal(Problem{Type: "about:blank", Title: "OK", Status: http.StatusOK})
+	if err != nil {
+		t.Fatalf("MarshalJSON returned error: %v", err)
+	}
+
+	var out map[string]interface{}
+	if err := json.Unmarshal(body, &out); err != nil {
+		t.Fatalf("failed to decode marshaled problem: %v", err)
+	}
+	if _, ok := out["detail"]; ok {
+		t.Errorf("expected no detail key, got %v", out["detail"])
+	}
+	if _, ok := out["instance"]; ok {
+		t.Errorf("expected no instance key, got %v", out["instance"])
+	}
+}
+
+func TestRespondProblem_WritesStatusAndContentType(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	RespondProblem(rec, req, ProblemNotFound, "scenario 42 not found")
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Content-Type = %q, want application/problem+json", ct)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode problem body: %v", err)
+	}
+	if body["detail"] != "scenario 42 not found" {
+		t.Errorf("detail = %v, want %q", body["detail"], "scenario 42 not found")
+	}
+}
+
+func TestRespondProblem_AttachesRequestIDFromContext(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(WithRequestID(context.Background(), "req-123"))
+
+	RespondProblem(rec, req, ProblemInternal, "boom")
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode problem body: %v", err)
+	}
+	if body["request_id"] != "req-123" {
+		t.Errorf("request_id = %v, want %q", body["request_id"], "req-123")
+	}
+}
+
+func TestRespondProblem_DoesNotMutateCallersExtensionsMap(t *testing.T) {
+	shared := map[string]interface{}{"code": "boom_code"}
+
+	reqA := httptest.NewRequest(http.MethodGet, "/", nil)
+	reqA = reqA.WithContext(WithRequestID(context.Background(), "req-a"))
+	RespondProblem(httptest.NewRecorder(), reqA, ProblemInternal, "boom", shared)
+
+	reqB := httptest.NewRequest(http.MethodGet, "/", nil)
+	reqB = reqB.WithContext(WithRequestID(context.Background(), "req-b"))
+	recB := httptest.NewRecorder()
+	RespondProblem(recB, reqB, ProblemInternal, "boom", shared)
+
+	if _, ok := shared["request_id"]; ok {
+		t.Fatalf("expected RespondProblem not to mutate the caller's extensions map, got %v", shared)
+	}
+
+	var bodyB map[string]interface{}
+	if err := json.Unmarshal(recB.Body.Bytes(), &bodyB); err != nil {
+		t.Fatalf("failed to decode problem body: %v", err)
+	}
+	if bodyB["request_id"] != "req-b" {
+		t.Errorf("request_id = %v, want %q (must not leak from the first call)", bodyB["request_id"], "req-b")
+	}
+}
+
+func TestRespondError_UsesCatalogTitleAndMessageAsDetail(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	RespondError(rec, http.StatusBadRequest, "missing field: name")
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Content-Type = %q, want application/problem+json", ct)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode problem body: %v", err)
+	}
+	if body["title"] != ProblemValidation.Title {
+		t.Errorf("title = %v, want %q", body["title"], ProblemValidation.Title)
+	}
+	if body["detail"] != "missing field: name" {
+		t.Errorf("detail = %v, want %q", body["detail"], "missing field: name")
+	}
+}
+
+func TestRespondError_UnmappedStatusFallsBackToStatusText(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	RespondError(rec, http.StatusTeapot, "I am a teapot after all")
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode problem body: %v", err)
+	}
+	if body["type"] != "about:blank" {
+		t.Errorf("type = %v, want about:blank", body["type"])
+	}
+	if body["title"] != http.StatusText(http.StatusTeapot) {
+		t.Errorf("title = %v, want %q", body["title"], http.StatusText(http.StatusTeapot))
+	}
+}
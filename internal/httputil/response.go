@@ -1,21 +1,54 @@
 package httputil
 
 import (
-	"encoding/json"
+	"bytes"
 	"log"
 	"net/http"
+	"sync"
 )
 
-// RespondJSON sends a JSON response with the given status code
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// RespondJSON sends a JSON response with the given status code. data is
+// marshaled to a pooled buffer before anything is written to w, so a
+// marshaling error produces a proper 500 Problem body instead of a
+// truncated 200 with a partially-written one.
 func RespondJSON(w http.ResponseWriter, status int, data interface{}) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer bufferPool.Put(buf)
+
+	if err := defaultCodec.NewEncoder(buf).Encode(data); err != nil {
+		log.Printf("httputil: error encoding response: %v", err)
+		writeProblem(w, Problem{
+			Type:   ProblemInternal.Type,
+			Title:  ProblemInternal.Title,
+			Status: ProblemInternal.Status,
+			Detail: "failed to encode response body",
+		})
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		log.Printf("Error encoding response: %v", err)
+	if _, err := buf.WriteTo(w); err != nil {
+		log.Printf("httputil: error writing response: %v", err)
 	}
 }
 
-// RespondError sends a JSON error response with the given status code
+// RespondError sends an RFC 7807 application/problem+json error response
+// for the given status code. Title is taken from the catalog entry for
+// status (or http.StatusText when status has no entry); message is always
+// used as Detail. Callers that know which kind of problem they're reporting
+// should prefer RespondProblem.
 func RespondError(w http.ResponseWriter, status int, message string) {
-	RespondJSON(w, status, map[string]string{"error": message})
+	pt := problemTypeForStatus(status)
+	writeProblem(w, Problem{
+		Type:   pt.Type,
+		Title:  pt.Title,
+		Status: status,
+		Detail: message,
+	})
 }
@@ -0,0 +1,82 @@
+package httputil
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Event is a single Server-Sent Event. Data is marshaled through the
+// package Codec, same as RespondJSON.
+type Event struct {
+	ID   string
+	Type string
+	Data interface{}
+}
+
+// sseHeartbeat is how often RespondSSE sends a comment-only keep-alive
+// line while events is otherwise idle, so intermediate proxies don't treat
+// the connection as dead and close it.
+const sseHeartbeat = 15 * time.Second
+
+// RespondSSE streams events to w as Server-Sent Events until events is
+// closed or the request's context is done (client disconnect). Callers
+// that want resume support should read r.Header.Get("Last-Event-ID")
+// before constructing events and skip anything already delivered; see
+// ExampleScenarioRecomputeHandler.
+func RespondSSE(w http.ResponseWriter, r *http.Request, events <-chan Event) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		RespondProblem(w, r, ProblemInternal, "response writer does not support streaming")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // disable nginx response buffering
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case ev, open := <-events:
+			if !open {
+				return
+			}
+			if err := writeSSEEvent(w, ev); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeSSEEvent(w http.ResponseWriter, ev Event) error {
+	if ev.ID != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", ev.ID); err != nil {
+			return err
+		}
+	}
+	if ev.Type != "" {
+		if _, err := fmt.Fprintf(w, "event: %s\n", ev.Type); err != nil {
+			return err
+		}
+	}
+	body, err := defaultCodec.Marshal(ev.Data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", body)
+	return err
+}
@@ -0,0 +1,85 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRespondSSE_WritesEventLines(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	events := make(chan Event, 1)
+	events <- Event{ID: "1", Type: "progress", Data: map[string]int{"step": 1}}
+	close(events)
+
+	RespondSSE(rec, req, events)
+
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{"id: 1\n", "event: progress\n", `data: {"step":1}`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body %q does not contain %q", body, want)
+		}
+	}
+}
+
+func TestRespondSSE_TerminatesOnClientDisconnect(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+	events := make(chan Event) // never closed - only ctx cancellation should end the stream
+	done := make(chan struct{})
+	go func() {
+		RespondSSE(rec, req, events)
+		close(done)
+	}()
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("RespondSSE did not return after the request context was canceled")
+	}
+}
+
+// nonFlushingWriter implements http.ResponseWriter but not http.Flusher, to
+// exercise RespondSSE's fallback for writers that can't stream.
+type nonFlushingWriter struct {
+	header http.Header
+	status int
+	body   strings.Builder
+}
+
+func newNonFlushingWriter() *nonFlushingWriter {
+	return &nonFlushingWriter{header: make(http.Header)}
+}
+
+func (w *nonFlushingWriter) Header() http.Header { return w.header }
+
+func (w *nonFlushingWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
+
+func (w *nonFlushingWriter) WriteHeader(status int) { w.status = status }
+
+func TestRespondSSE_RespondsProblemWhenWriterCannotStream(t *testing.T) {
+	w := newNonFlushingWriter()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	RespondSSE(w, req, make(chan Event))
+
+	if w.status != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.status, http.StatusInternalServerError)
+	}
+	if ct := w.header.Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Content-Type = %q, want application/problem+json", ct)
+	}
+}
@@ -0,0 +1,29 @@
+package httputil
+
+import (
+	"log"
+	"net/http"
+)
+
+// RespondJSONStream writes status once, then streams each value received
+// from ch as a newline-delimited JSON document (application/x-ndjson),
+// flushing after every value. It is meant for long-running decision-theatre
+// simulations that want to push intermediate results to the client as they
+// become available rather than buffering the whole run. It returns once ch
+// is closed.
+func RespondJSONStream(w http.ResponseWriter, status int, ch <-chan interface{}) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(status)
+
+	flusher, _ := w.(http.Flusher)
+	enc := defaultCodec.NewEncoder(w)
+	for v := range ch {
+		if err := enc.Encode(v); err != nil {
+			log.Printf("httputil: error encoding stream value: %v", err)
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
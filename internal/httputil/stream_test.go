@@ -0,0 +1,56 @@
+package httputil
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRespondJSONStream_WritesNewlineDelimitedJSON(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ch := make(chan interface{}, 2)
+	ch <- map[string]int{"step": 1}
+	ch <- map[string]int{"step": 2}
+	close(ch)
+
+	RespondJSONStream(rec, http.StatusOK, ch)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q, want application/x-ndjson", ct)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(rec.Body.Bytes()))
+	var lines []map[string]int
+	for scanner.Scan() {
+		var v map[string]int
+		if err := json.Unmarshal(scanner.Bytes(), &v); err != nil {
+			t.Fatalf("failed to decode line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, v)
+	}
+
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	if lines[0]["step"] != 1 || lines[1]["step"] != 2 {
+		t.Errorf("unexpected stream contents: %+v", lines)
+	}
+}
+
+func TestRespondJSONStream_ReturnsWhenChannelClosedImmediately(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ch := make(chan interface{})
+	close(ch)
+
+	RespondJSONStream(rec, http.StatusOK, ch)
+
+	if rec.Body.Len() != 0 {
+		t.Errorf("expected no body for an empty stream, got %q", rec.Body.String())
+	}
+}
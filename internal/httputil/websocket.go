@@ -0,0 +1,32 @@
+package httputil
+
+import (
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+// upgrader is the default gorilla/websocket upgrader used by Upgrade. Its
+// CheckOrigin is the library default (same-origin); override it before
+// serving clients that need cross-origin connections.
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	Error: func(w http.ResponseWriter, r *http.Request, status int, reason error) {
+		pt := problemTypeForStatus(status)
+		writeProblem(w, Problem{
+			Type:   pt.Type,
+			Title:  pt.Title,
+			Status: status,
+			Detail: reason.Error(),
+		})
+	},
+}
+
+// Upgrade upgrades an HTTP connection to a WebSocket connection, so the
+// webview UI can subscribe to live updates instead of polling. On failure
+// the upgrader has already written a Problem response; the returned error
+// should simply be logged.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*websocket.Conn, error) {
+	return upgrader.Upgrade(w, r, nil)
+}
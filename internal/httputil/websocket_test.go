@@ -0,0 +1,24 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUpgrade_NonWebSocketRequestRespondsProblem(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil) // missing Upgrade/Connection headers
+
+	_, err := Upgrade(rec, req)
+	if err == nil {
+		t.Fatal("expected Upgrade to fail for a non-websocket request")
+	}
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Content-Type = %q, want application/problem+json", ct)
+	}
+	if rec.Code == http.StatusOK {
+		t.Errorf("status = %d, want a non-200 error status", rec.Code)
+	}
+}